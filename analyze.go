@@ -0,0 +1,144 @@
+package confusables
+
+// Report is the result of analyzing a string for mixed-script and
+// whole-script confusability, per Unicode TR39 §4:
+// http://www.unicode.org/reports/tr39/#Confusable_Detection
+type Report struct {
+	// MixedScript is true if s contains characters from more than one
+	// script (ignoring Common and Inherited) that are not jointly covered
+	// by RestrictionLevel's HighlyRestrictive allowances.
+	MixedScript bool
+	// WholeScriptConfusable is true if s, read as written in its own
+	// script(s), could be entirely re-spelled using a single other script.
+	WholeScriptConfusable bool
+	// ConfusedScripts holds the pair of scripts responsible for a whole-
+	// script confusable: s's own script and the other script it collides
+	// with. It is unset when WholeScriptConfusable is false.
+	ConfusedScripts [2]string
+}
+
+// IsConfusable reports whether s1 and s2 share a skeleton, i.e. they are
+// confusable with each other under TR39 §4 "Confusable Detection".
+func IsConfusable(s1, s2 string) bool {
+	return Skeleton(s1) == Skeleton(s2)
+}
+
+// IsMixedScriptConfusable reports whether s mixes scripts in a way that is
+// not covered by one of RestrictionLevel's HighlyRestrictive allowances,
+// making it a candidate for TR39's mixed-script confusable detection.
+func IsMixedScriptConfusable(s string) bool {
+	return Analyze(s).MixedScript
+}
+
+// IsWholeScriptConfusable reports whether s could be entirely re-spelled in
+// a script different from its own, per TR39's whole-script confusable
+// detection.
+func IsWholeScriptConfusable(s string) bool {
+	return Analyze(s).WholeScriptConfusable
+}
+
+// Analyze runs both the mixed-script and whole-script confusable checks
+// over s and returns a Report describing what, if anything, collided.
+func Analyze(s string) Report {
+	var report Report
+
+	sets := resolvedScriptSets(s)
+	if len(sets) > 1 && len(commonScripts(sets)) == 0 {
+		report.MixedScript = true
+		isAllowed := false
+		for _, combo := range highlyRestrictiveSets {
+			if coveredBy(sets, toSet(combo)) {
+				isAllowed = true
+				break
+			}
+		}
+		if isAllowed || coveredByLatinPlusOne(sets, recommendedScripts) {
+			report.MixedScript = false
+		}
+	}
+
+	own, other, ok := wholeScriptConfusable(s)
+	if ok {
+		report.WholeScriptConfusable = true
+		report.ConfusedScripts = [2]string{own, other}
+	}
+
+	return report
+}
+
+// wholeScriptConfusables maps a rune to the set of scripts whose
+// confusable set contains it, i.e. the scripts s could be mistaken for
+// character-by-character. It is derived from confusables.txt by grouping
+// its entries into skeleton buckets; see makeWholeScriptTable in
+// maketables.go.
+
+// wholeScriptConfusable finds a script, other than the one(s) s is itself
+// written in, that could stand in for every character of s.
+func wholeScriptConfusable(s string) (own, other string, ok bool) {
+	ownSets := resolvedScriptSets(s)
+	ownScripts := commonScripts(ownSets)
+	if len(ownScripts) == 0 {
+		// s's characters share no script (it mixes scripts, or has no
+		// non-Common/Inherited characters): fall back to the union of
+		// scripts actually used as s's "own" scripts.
+		ownScripts = make(map[string]bool)
+		for _, set := range ownSets {
+			for name := range set {
+				ownScripts[name] = true
+			}
+		}
+	}
+
+	var candidates map[string]bool
+	for _, r := range s {
+		scripts, exists := wholeScriptConfusables[r]
+		if !exists {
+			// No recorded confusable-script entry for r: fall back to its
+			// own resolved script(s) rather than aborting the whole check,
+			// so "boring" characters (digits, underscores, punctuation)
+			// don't hide a confusable found elsewhere in s.
+			scripts = scriptsOf(r)
+		}
+
+		allIgnorable := true
+		set := make(map[string]bool, len(scripts))
+		for _, name := range scripts {
+			if ignorableScript(name) {
+				continue
+			}
+			allIgnorable = false
+			if !ownScripts[name] {
+				set[name] = true
+			}
+		}
+		if allIgnorable {
+			// Common/Inherited characters match any script and don't
+			// narrow the candidate set.
+			continue
+		}
+
+		if candidates == nil {
+			candidates = set
+		} else {
+			candidates = intersect(candidates, set)
+		}
+		if len(candidates) == 0 {
+			return "", "", false
+		}
+	}
+	if candidates == nil {
+		// Every character was Common/Inherited: there's no "other" script
+		// to report.
+		return "", "", false
+	}
+
+	for name := range ownScripts {
+		own = name
+		break
+	}
+	for name := range candidates {
+		other = name
+		break
+	}
+	return own, other, true
+}
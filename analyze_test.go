@@ -0,0 +1,29 @@
+package confusables
+
+import "testing"
+
+func TestIsConfusable(t *testing.T) {
+	if !IsConfusable("ρ⍺у𝓅𝒂ן", "𝔭𝒶ỿ𝕡𝕒ℓ") {
+		t.Errorf("expected the two paypal look-alikes to be confusable")
+	}
+	if IsConfusable("paypal", "example") {
+		t.Errorf("did not expect unrelated strings to be confusable")
+	}
+}
+
+func TestAnalyzeASCII(t *testing.T) {
+	report := Analyze("paypal")
+	if report.MixedScript || report.WholeScriptConfusable {
+		t.Errorf("Analyze(%q) = %+v, want no confusability reported", "paypal", report)
+	}
+}
+
+func TestIsWholeScriptConfusableIgnoresBoringCharacters(t *testing.T) {
+	// "pаypal_1" mixes a Cyrillic "а" lookalike with plain ASCII letters,
+	// a digit, and an underscore. The digit and underscore have no entry
+	// in the confusable-script table and must not, on their own, hide the
+	// whole-script confusable contributed by the rest of the string.
+	if !IsWholeScriptConfusable("pаypal_1") {
+		t.Errorf("expected %+q to be reported as whole-script confusable", "pаypal_1")
+	}
+}
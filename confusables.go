@@ -3,26 +3,52 @@
 package confusables
 
 import (
-	"unicode/utf8"
-
-	"golang.org/x/text/unicode/norm"
+	"bytes"
 )
 
-// Skeleton converts a string to its skeleton form as described in
-// http://www.unicode.org/reports/tr39/#Confusable_Detection
+// Skeleton converts a string to its skeleton form using the package's
+// default table, as described in
+// http://www.unicode.org/reports/tr39/#Confusable_Detection. See Table for
+// ways to customize the confusable mappings used.
 func Skeleton(s string) string {
-	s = norm.NFKD.String(s)
-	for i, w := 0, 0; i < len(s); i += w {
-		char, width := utf8.DecodeRuneInString(s[i:])
-		replacement, exists := confusablesMap[char]
-		if exists {
-			s = s[:i] + replacement + s[i+width:]
-			w = len(replacement)
-		} else {
-			w = width
-		}
-	}
-	s = norm.NFKD.String(s)
-
-	return s
+	return DefaultTable().Skeleton(s)
+}
+
+// SkeletonBytes appends the skeleton form of src to dst and returns the
+// extended buffer, in the style of norm.Form.Append. It avoids the string
+// allocations Skeleton incurs, for callers already working in []byte, and
+// uses the package's default table. See (*Table).SkeletonBytes to use a
+// custom table.
+func SkeletonBytes(dst, src []byte) []byte {
+	return DefaultTable().SkeletonBytes(dst, src)
+}
+
+// SkeletonWriter is an io.Writer that accumulates written bytes and computes
+// their combined skeleton on demand, so large inputs can be streamed in with
+// io.Copy rather than held as one string up front.
+type SkeletonWriter struct {
+	table *Table
+	buf   bytes.Buffer
+}
+
+// NewSkeletonWriter returns a ready-to-use SkeletonWriter backed by the
+// package's default table. See (*Table).NewSkeletonWriter to use a custom
+// table.
+func NewSkeletonWriter() *SkeletonWriter {
+	return DefaultTable().NewSkeletonWriter()
+}
+
+// Write implements io.Writer, buffering p for later normalization.
+func (w *SkeletonWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// Bytes returns the skeleton of everything written so far.
+func (w *SkeletonWriter) Bytes() []byte {
+	return w.table.SkeletonBytes(nil, w.buf.Bytes())
+}
+
+// String returns the skeleton of everything written so far.
+func (w *SkeletonWriter) String() string {
+	return string(w.Bytes())
 }
@@ -1,6 +1,7 @@
 package confusables
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -44,6 +45,25 @@ func TestCompareDifferent(t *testing.T) {
 	}
 }
 
+func TestSkeletonBytes(t *testing.T) {
+	s := "ρ⍺у𝓅𝒂ן"
+	expected := "paypal"
+
+	if got := string(SkeletonBytes(nil, []byte(s))); got != expected {
+		t.Errorf("SkeletonBytes(%s) = %s, want %s", s, got, expected)
+	}
+}
+
+func TestSkeletonWriter(t *testing.T) {
+	w := NewSkeletonWriter()
+	w.Write([]byte("ρ⍺у"))
+	w.Write([]byte("𝓅𝒂ן"))
+
+	if got, expected := w.String(), "paypal"; got != expected {
+		t.Errorf("SkeletonWriter.String() = %s, want %s", got, expected)
+	}
+}
+
 func BenchmarkSkeletonNoop(b *testing.B) {
 	s := "skeleton"
 
@@ -59,3 +79,38 @@ func BenchmarkSkeleton(b *testing.B) {
 		Skeleton(s)
 	}
 }
+
+// skeletonInput builds an n-byte string out of repeated confusable
+// characters, for benchmarking Skeleton's scaling on larger inputs.
+func skeletonInput(n int) string {
+	const unit = "ѕ𝗄℮|е𝗍ο𝔫 "
+	var b strings.Builder
+	for b.Len() < n {
+		b.WriteString(unit)
+	}
+	return b.String()[:n]
+}
+
+func BenchmarkSkeleton1KB(b *testing.B) {
+	s := skeletonInput(1 << 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Skeleton(s)
+	}
+}
+
+func BenchmarkSkeleton100KB(b *testing.B) {
+	s := skeletonInput(100 << 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Skeleton(s)
+	}
+}
+
+func BenchmarkSkeleton1MB(b *testing.B) {
+	s := skeletonInput(1 << 20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Skeleton(s)
+	}
+}
@@ -0,0 +1,164 @@
+package confusables
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrDisallowedRune is returned by CanonicalIdentifier when s contains a
+// character outside XID_Continue, or violates this package's partial bidi
+// rule check (see bidiRuleViolation).
+var ErrDisallowedRune = errors.New("confusables: identifier contains a disallowed character")
+
+// CanonicalIdentifier canonicalizes s for identifier-collision comparison:
+// NFC, then full Unicode case folding (per CaseFolding.txt), then Skeleton,
+// then NFC again. It rejects strings containing characters outside
+// XID_Continue, or violating this package's partial bidi rule check,
+// returning ErrDisallowedRune. This is the single call services like IRC
+// daemons or username validators need to decide whether two identifiers
+// would be visually or semantically indistinguishable.
+//
+// This does not implement the full TR39 §5.4 bidi rule; see
+// bidiRuleViolation for exactly what is and isn't covered. Whether the
+// remaining gap matters is a product decision for whoever consumes this
+// package, not something this package can settle unilaterally: callers
+// with a stricter requirement should run their own UAX#9-based check
+// before or after calling CanonicalIdentifier.
+func CanonicalIdentifier(s string) (string, error) {
+	if !isXIDContinueString(s) {
+		return "", ErrDisallowedRune
+	}
+	if bidiRuleViolation(s) {
+		return "", ErrDisallowedRune
+	}
+
+	folded := caseFold(norm.NFC.String(s))
+	return norm.NFC.String(Skeleton(folded)), nil
+}
+
+// EqualFold reports whether a and b collide under CanonicalIdentifier's
+// canonicalization.
+func EqualFold(a, b string) bool {
+	ca, err := CanonicalIdentifier(a)
+	if err != nil {
+		return false
+	}
+	cb, err := CanonicalIdentifier(b)
+	if err != nil {
+		return false
+	}
+	return ca == cb
+}
+
+// caseFold applies full Unicode case folding using foldingMap, the table
+// generated from CaseFolding.txt's C (common) and F (full) status lines;
+// see maketables.go.
+func caseFold(s string) string {
+	var buf strings.Builder
+	buf.Grow(len(s))
+	for _, r := range s {
+		if folded, ok := foldingMap[r]; ok {
+			buf.WriteString(folded)
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// isXIDContinueString reports whether every rune in s has the XID_Continue
+// property, per xidContinue, generated from DerivedCoreProperties.txt; see
+// maketables.go.
+func isXIDContinueString(s string) bool {
+	for _, r := range s {
+		if !unicode.Is(xidContinue, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// rtlScripts are the right-to-left scripts bidiRuleViolation and
+// hasAmbiguousBidiDigits recognize. This covers TR39's most commonly cited
+// RTL scripts but, unlike a proper UAX#9 Bidi_Class lookup, is a
+// script-based approximation: it is not exhaustive of every RTL script in
+// Unicode (e.g. Old Hungarian, Adlam, Chorasmian are not included).
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko,
+	unicode.Mandaic,
+}
+
+func isRTLRune(r rune) bool {
+	for _, script := range rtlScripts {
+		if unicode.Is(script, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCombiningMark(r rune) bool {
+	return unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r)
+}
+
+// bidiRuleViolation is a narrow, partial check inspired by TR39 §5.4's bidi
+// rule (http://www.unicode.org/reports/tr39/#Bidi_Rule). For an identifier
+// containing a character from rtlScripts, it reports a violation if either:
+//
+//   - the identifier starts or ends with a combining mark (general category
+//     Mn or Me), which the real bidi rule also forbids (its Rule 1 and
+//     Rule 3 both constrain the first/last character's directional
+//     category, and a leading or trailing combining mark can never satisfy
+//     either); or
+//   - the identifier mixes European and Arabic-Indic digits (see
+//     hasAmbiguousBidiDigits), since the two numbering systems read in
+//     opposite visual order and make the identifier's digits ambiguous.
+//
+// It does NOT implement the rest of TR39's bidi rule: the full rule
+// constrains the directional category (per DerivedBidiClass.txt) of every
+// character in the identifier, not just the first/last and not just
+// digits, and this package does not generate a Bidi_Class table. Whether
+// that gap is acceptable is a product decision for this package's callers;
+// see CanonicalIdentifier.
+func bidiRuleViolation(s string) bool {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return false
+	}
+
+	hasRTL := false
+	for _, r := range runes {
+		if isRTLRune(r) {
+			hasRTL = true
+			break
+		}
+	}
+	if !hasRTL {
+		return false
+	}
+
+	if isCombiningMark(runes[0]) || isCombiningMark(runes[len(runes)-1]) {
+		return true
+	}
+
+	return hasAmbiguousBidiDigits(runes)
+}
+
+// hasAmbiguousBidiDigits reports whether runes contains both a European and
+// an Arabic-Indic digit. The caller (bidiRuleViolation) has already
+// confirmed runes contains an RTL character.
+func hasAmbiguousBidiDigits(runes []rune) bool {
+	var hasEuropeanDigit, hasArabicIndicDigit bool
+	for _, r := range runes {
+		switch {
+		case r >= '0' && r <= '9':
+			hasEuropeanDigit = true
+		case r >= 0x0660 && r <= 0x0669:
+			hasArabicIndicDigit = true
+		}
+	}
+	return hasEuropeanDigit && hasArabicIndicDigit
+}
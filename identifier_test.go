@@ -0,0 +1,49 @@
+package confusables
+
+import "testing"
+
+func TestEqualFoldCase(t *testing.T) {
+	if !EqualFold("Paypal", "paypal") {
+		t.Errorf("expected %q and %q to collide under EqualFold", "Paypal", "paypal")
+	}
+}
+
+func TestCanonicalIdentifierRejectsDisallowed(t *testing.T) {
+	if _, err := CanonicalIdentifier("nick‮"); err != ErrDisallowedRune {
+		t.Errorf("CanonicalIdentifier with a non-XID_Continue rune: got err %v, want ErrDisallowedRune", err)
+	}
+}
+
+func TestCanonicalIdentifierRejectsAmbiguousBidiDigits(t *testing.T) {
+	// Hebrew letter + a European digit + an Arabic-Indic digit: passes the
+	// XID_Continue check, so this exercises hasAmbiguousBidiDigits itself.
+	s := "א1٢"
+	if _, err := CanonicalIdentifier(s); err != ErrDisallowedRune {
+		t.Errorf("CanonicalIdentifier(%+q) with mixed digit systems: got err %v, want ErrDisallowedRune", s, err)
+	}
+}
+
+func TestCanonicalIdentifierAllowsHebrewWithEuropeanDigits(t *testing.T) {
+	s := "א12"
+	if _, err := CanonicalIdentifier(s); err != nil {
+		t.Errorf("CanonicalIdentifier(%+q) = _, %v, want no error", s, err)
+	}
+}
+
+func TestCanonicalIdentifierRejectsRTLEndingInCombiningMark(t *testing.T) {
+	// Hebrew letter Alef followed by the combining point Sheva: passes
+	// XID_Continue, so this exercises bidiRuleViolation's first/last check.
+	s := "אְ"
+	if _, err := CanonicalIdentifier(s); err != ErrDisallowedRune {
+		t.Errorf("CanonicalIdentifier(%+q) ending in a combining mark: got err %v, want ErrDisallowedRune", s, err)
+	}
+}
+
+func TestCanonicalIdentifierRejectsAmbiguousBidiDigitsBeyondHebrewArabic(t *testing.T) {
+	// Syriac letter + a European digit + an Arabic-Indic digit: exercises
+	// bidiRuleViolation's broadened RTL script detection.
+	s := "ܐ" + "1" + "٢"
+	if _, err := CanonicalIdentifier(s); err != ErrDisallowedRune {
+		t.Errorf("CanonicalIdentifier(%+q) with mixed digit systems in a Syriac identifier: got err %v, want ErrDisallowedRune", s, err)
+	}
+}
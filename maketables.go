@@ -22,13 +22,29 @@ import (
 func main() {
 	flag.Parse()
 	loadUnicodeData()
-	makeTables()
+	loadScriptData()
+	loadFoldingData()
+	loadXIDData()
+
+	// All generated declarations must come after this single import block;
+	// the individual make* functions below only emit declarations.
+	out := "import \"unicode\"\n\n"
+	out += makeTables()
+	out += makeScriptTables()
+	out += makeWholeScriptTable()
+	out += makeFoldingTable()
+	out += makeXIDTable()
+	WriteGoFile("tables.go", "confusables", []byte(out))
 }
 
 var url = flag.String("url",
 	"http://www.unicode.org/Public/security/latest/",
 	"URL of Unicode database directory")
 
+var ucdURL = flag.String("ucd-url",
+	"http://www.unicode.org/Public/UCD/latest/ucd/",
+	"URL of the Unicode Character Database directory, for Scripts.txt, ScriptExtensions.txt, CaseFolding.txt and DerivedCoreProperties.txt")
+
 var localFiles = flag.Bool("local",
 	false,
 	"data files have been copied to the current directory; for debugging only")
@@ -47,6 +63,10 @@ const (
 )
 
 func openReader(file string) (input io.ReadCloser) {
+	return openReaderFrom(*url, file)
+}
+
+func openReaderFrom(base, file string) (input io.ReadCloser) {
 	if *localFiles {
 		f, err := os.Open(file)
 		if err != nil {
@@ -54,7 +74,7 @@ func openReader(file string) (input io.ReadCloser) {
 		}
 		input = f
 	} else {
-		path := *url + file
+		path := base + file
 		log.Println("Downloading " + path)
 		resp, err := http.Get(path)
 		if err != nil {
@@ -157,7 +177,7 @@ func loadUnicodeData() {
 // - confuse "ʀ" (Latin small R) with "R"
 // - confuse "ኮ" (Ethiopic syllabel Ko) with "r"
 // - various additions as below
-func makeTables() {
+func makeTables() string {
 	out := fmt.Sprintf("%s\n", originalHeader)
 	out += fmt.Sprint("var confusablesMap = map[rune]string{\n\n")
 	for _, c := range confusables {
@@ -219,7 +239,303 @@ func makeTables() {
 	out += fmt.Sprintf("0x%.8X: %+q,\n", 'π', "n")
 	out += fmt.Sprintln("}")
 
-	WriteGoFile("tables.go", "confusables", []byte(out))
+	return out
+}
+
+// scriptRange is one line of Scripts.txt: a contiguous block of code points
+// assigned to a single script.
+type scriptRange struct {
+	lo, hi rune
+	name   string
+}
+
+// scriptExtRange is one line of ScriptExtensions.txt: a contiguous block of
+// code points sharing the same resolved (Script_Extensions) script set.
+type scriptExtRange struct {
+	lo, hi rune
+	names  []string
+}
+
+var scripts []scriptRange
+var scriptExts []scriptExtRange
+
+// parseRangeLine splits a Scripts.txt / ScriptExtensions.txt data line of
+// the form "0041..005A    ; Latin # ..." or "0041 ; Latin # ...." into its
+// code point range and the raw value field.
+func parseRangeLine(line string) (lo, hi rune, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if len(line) == 0 || line[0] == '#' {
+		return 0, 0, "", false
+	}
+	fields := strings.SplitN(line, ";", 2)
+	if len(fields) != 2 {
+		return 0, 0, "", false
+	}
+	value = strings.TrimSpace(strings.SplitN(fields[1], "#", 2)[0])
+	points := strings.TrimSpace(fields[0])
+	if dotdot := strings.Index(points, ".."); dotdot >= 0 {
+		lo = parsePoint(points[:dotdot], line)
+		hi = parsePoint(points[dotdot+2:], line)
+	} else {
+		lo = parsePoint(points, line)
+		hi = lo
+	}
+	return lo, hi, value, true
+}
+
+func parseScriptLine(line string) {
+	lo, hi, name, ok := parseRangeLine(line)
+	if !ok {
+		return
+	}
+	scripts = append(scripts, scriptRange{lo, hi, name})
+}
+
+func parseScriptExtLine(line string) {
+	lo, hi, value, ok := parseRangeLine(line)
+	if !ok {
+		return
+	}
+	scriptExts = append(scriptExts, scriptExtRange{lo, hi, strings.Fields(value)})
+}
+
+func loadScriptData() {
+	f := openReaderFrom(*ucdURL, "Scripts.txt")
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parseScriptLine(scanner.Text())
+	}
+	if scanner.Err() != nil {
+		log.Fatal(scanner.Err())
+	}
+
+	fe := openReaderFrom(*ucdURL, "ScriptExtensions.txt")
+	defer fe.Close()
+	scanner = bufio.NewScanner(fe)
+	for scanner.Scan() {
+		parseScriptExtLine(scanner.Text())
+	}
+	if scanner.Err() != nil {
+		log.Fatal(scanner.Err())
+	}
+}
+
+// makeWholeScriptTable emits wholeScriptConfusables, a map from rune to the
+// set of scripts that contain a confusable of it. It is derived from the
+// confusables relation already loaded by loadUnicodeData: runes linked,
+// directly or transitively, by a single-rune-to-single-rune confusablesMap
+// entry are grouped into a skeleton bucket, and every rune in a bucket is
+// tagged with the union of scripts used anywhere in that bucket.
+//
+// Multi-rune targets (e.g. a source character whose confusable is a two
+// character digraph) are deliberately NOT unioned here: doing so would
+// transitively merge every source that happens to share just one component
+// of the digraph (e.g. both "x -> rn" and "y -> r" would hub-merge through
+// 'r', even though x and y have nothing to do with each other), producing
+// a handful of giant, unrelated buckets instead of tight skeleton groups.
+//
+// This approximates the upstream confusablesWholeScript.txt table, which
+// Unicode derives the same way: by grouping confusables.txt entries into
+// per-skeleton buckets and recording which scripts appear in each bucket.
+func makeWholeScriptTable() string {
+	parent := make(map[rune]rune)
+	var find func(r rune) rune
+	find = func(r rune) rune {
+		if p, ok := parent[r]; ok && p != r {
+			root := find(p)
+			parent[r] = root
+			return root
+		}
+		parent[r] = r
+		return r
+	}
+	union := func(a, b rune) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[ra] = rb
+		}
+	}
+
+	for _, c := range confusables {
+		find(c.k)
+		if len(c.v) == 1 {
+			find(c.v[0])
+			union(c.k, c.v[0])
+		}
+	}
+
+	buckets := make(map[rune][]rune)
+	for r := range parent {
+		root := find(r)
+		buckets[root] = append(buckets[root], r)
+	}
+
+	wholeScript := make(map[rune]map[string]bool)
+	for _, members := range buckets {
+		scripts := make(map[string]bool)
+		for _, r := range members {
+			for _, name := range scriptsOfGenTime(r) {
+				scripts[name] = true
+			}
+		}
+		for _, r := range members {
+			wholeScript[r] = scripts
+		}
+	}
+
+	out := fmt.Sprint("\nvar wholeScriptConfusables = map[rune][]string{\n\n")
+	for r, scripts := range wholeScript {
+		out += fmt.Sprintf("0x%.8X: {", r)
+		for name := range scripts {
+			out += fmt.Sprintf("%+q, ", name)
+		}
+		out += fmt.Sprint("},\n")
+	}
+	out += fmt.Sprintln("}")
+
+	return out
+}
+
+// scriptsOfGenTime is the generator-time equivalent of the runtime
+// scriptsOf helper in scripts.go: it looks scripts up directly from the
+// in-memory Scripts.txt/ScriptExtensions.txt data rather than the generated
+// tables, since tables.go doesn't exist yet while this program is running.
+func scriptsOfGenTime(r rune) []string {
+	for _, ext := range scriptExts {
+		if r >= ext.lo && r <= ext.hi {
+			return ext.names
+		}
+	}
+	for _, s := range scripts {
+		if r >= s.lo && r <= s.hi {
+			return []string{s.name}
+		}
+	}
+	return nil
+}
+
+// foldEntry is one line of CaseFolding.txt: a code point's full (status C
+// or F) case folding.
+type foldEntry struct {
+	k rune
+	v []rune
+}
+
+var foldings []foldEntry
+
+func parseFoldingLine(line string) {
+	if len(line) == 0 || line[0] == '#' {
+		return
+	}
+	fields := strings.Split(line, "; ")
+	if len(fields) < 4 {
+		return
+	}
+	status := strings.TrimSpace(fields[1])
+	if status != "C" && status != "F" {
+		// Only common and full foldings make up full case folding; simple
+		// (S) and Turkic-only (T) mappings are excluded.
+		return
+	}
+
+	k := parsePoint(strings.TrimSpace(fields[0]), line)
+	var v []rune
+	for _, cp := range strings.Fields(fields[2]) {
+		v = append(v, parsePoint(cp, line))
+	}
+	foldings = append(foldings, foldEntry{k, v})
+}
+
+func loadFoldingData() {
+	f := openReaderFrom(*ucdURL, "CaseFolding.txt")
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parseFoldingLine(scanner.Text())
+	}
+	if scanner.Err() != nil {
+		log.Fatal(scanner.Err())
+	}
+}
+
+// makeFoldingTable emits foldingMap, the rune -> full-case-folded-string
+// table used by CanonicalIdentifier.
+func makeFoldingTable() string {
+	out := fmt.Sprint("\nvar foldingMap = map[rune]string{\n\n")
+	for _, f := range foldings {
+		out += fmt.Sprintf("0x%.8X: %+q,\n", f.k, string(f.v))
+	}
+	out += fmt.Sprintln("}")
+	return out
+}
+
+var xidContinueRanges []scriptRange
+
+func parseXIDLine(line string) {
+	lo, hi, prop, ok := parseRangeLine(line)
+	if !ok || prop != "XID_Continue" {
+		return
+	}
+	xidContinueRanges = append(xidContinueRanges, scriptRange{lo, hi, prop})
+}
+
+func loadXIDData() {
+	f := openReaderFrom(*ucdURL, "DerivedCoreProperties.txt")
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parseXIDLine(scanner.Text())
+	}
+	if scanner.Err() != nil {
+		log.Fatal(scanner.Err())
+	}
+}
+
+// makeXIDTable emits xidContinue, the unicode.RangeTable of code points
+// with the XID_Continue property, derived from DerivedCoreProperties.txt.
+func makeXIDTable() string {
+	out := fmt.Sprint("\nvar xidContinue = &unicode.RangeTable{\n R16: []unicode.Range16{},\n R32: []unicode.Range32{\n\n")
+	for _, r := range xidContinueRanges {
+		out += fmt.Sprintf("{0x%.8X, 0x%.8X, 1},\n", r.lo, r.hi)
+	}
+	out += fmt.Sprint("},\n}\n")
+	return out
+}
+
+// makeScriptTables emits scriptRanges, the per-script unicode.RangeTable
+// derived from Scripts.txt, and scriptExtensions, the rune -> resolved
+// script set overrides derived from ScriptExtensions.txt (only runes whose
+// Script_Extensions differ from their plain Script are listed there).
+func makeScriptTables() string {
+	byScript := make(map[string][]scriptRange)
+	for _, r := range scripts {
+		byScript[r.name] = append(byScript[r.name], r)
+	}
+
+	out := fmt.Sprint("\nvar scriptRanges = map[string]*unicode.RangeTable{\n\n")
+	for name, ranges := range byScript {
+		out += fmt.Sprintf("%+q: {\n R16: []unicode.Range16{},\n R32: []unicode.Range32{\n", name)
+		for _, r := range ranges {
+			out += fmt.Sprintf("{0x%.8X, 0x%.8X, 1},\n", r.lo, r.hi)
+		}
+		out += fmt.Sprint("},\n},\n\n")
+	}
+	out += fmt.Sprintln("}")
+
+	out += fmt.Sprint("\nvar scriptExtensions = map[rune][]string{\n\n")
+	for _, r := range scriptExts {
+		for cp := r.lo; cp <= r.hi; cp++ {
+			out += fmt.Sprintf("0x%.8X: {", cp)
+			for _, name := range r.names {
+				out += fmt.Sprintf("%+q, ", name)
+			}
+			out += fmt.Sprint("},\n")
+		}
+	}
+	out += fmt.Sprintln("}")
+
+	return out
 }
 
 const header = `// This file was generated by go generate; DO NOT EDIT
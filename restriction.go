@@ -0,0 +1,271 @@
+package confusables
+
+import "unicode"
+
+// IdentifierType is the Restriction Level an identifier satisfies, as
+// defined by Unicode TR39 §5 "Restriction Level Detection":
+// http://www.unicode.org/reports/tr39/#Restriction_Level_Detection
+//
+// The values are ordered from most to least restrictive; a lower value
+// implies every guarantee of the higher values listed after it.
+type IdentifierType int
+
+const (
+	// ASCIIOnly identifiers contain only ASCII characters.
+	ASCIIOnly IdentifierType = iota
+	// SingleScript identifiers have characters that all resolve to a
+	// single script (after applying Script_Extensions and ignoring Common
+	// and Inherited).
+	SingleScript
+	// HighlyRestrictive identifiers are covered by Latin plus one other
+	// Recommended script, or by one of the allowed logogram combinations:
+	// Han+Hiragana+Katakana, Han+Bopomofo, or Han+Hangul.
+	HighlyRestrictive
+	// ModeratelyRestrictive identifiers are covered by Latin plus any one
+	// other Recommended or Aspirational script.
+	ModeratelyRestrictive
+	// MinimallyRestrictive identifiers contain no characters from Excluded
+	// or Limited_Use scripts, but are otherwise unconstrained.
+	MinimallyRestrictive
+	// Unrestricted identifiers may contain characters from any script.
+	Unrestricted
+)
+
+func (t IdentifierType) String() string {
+	switch t {
+	case ASCIIOnly:
+		return "ASCIIOnly"
+	case SingleScript:
+		return "SingleScript"
+	case HighlyRestrictive:
+		return "HighlyRestrictive"
+	case ModeratelyRestrictive:
+		return "ModeratelyRestrictive"
+	case MinimallyRestrictive:
+		return "MinimallyRestrictive"
+	case Unrestricted:
+		return "Unrestricted"
+	default:
+		return "Unknown"
+	}
+}
+
+// recommendedScripts is the Unicode "Recommended" script list used when
+// computing the ModeratelyRestrictive and HighlyRestrictive levels. See
+// http://www.unicode.org/reports/tr39/#Table_Recommended_Scripts
+var recommendedScripts = map[string]bool{
+	"Arabic": true, "Armenian": true, "Bengali": true, "Bopomofo": true,
+	"Cyrillic": true, "Devanagari": true, "Ethiopic": true, "Georgian": true,
+	"Greek": true, "Gujarati": true, "Gurmukhi": true, "Han": true,
+	"Hangul": true, "Hebrew": true, "Hiragana": true, "Kannada": true,
+	"Katakana": true, "Khmer": true, "Lao": true, "Latin": true,
+	"Malayalam": true, "Myanmar": true, "Oriya": true, "Sinhala": true,
+	"Tamil": true, "Telugu": true, "Thai": true, "Tibetan": true,
+}
+
+// excludedOrLimitedUseScripts approximates TR39's Excluded and Limited_Use
+// script lists (http://www.unicode.org/reports/tr39/#Limited_Use_Scripts):
+// scripts that disqualify an identifier from MinimallyRestrictive and,
+// below, from ModeratelyRestrictive/HighlyRestrictive too.
+var excludedOrLimitedUseScripts = map[string]bool{
+	// Excluded (historic/deprecated) scripts.
+	"Linear_A": true, "Linear_B": true, "Cypriot": true, "Old_Italic": true,
+	"Gothic": true, "Deseret": true, "Shavian": true, "Osmanya": true,
+	"Cuneiform": true, "Egyptian_Hieroglyphs": true,
+
+	// Limited_Use scripts.
+	"Adlam": true, "Balinese": true, "Bamum": true, "Batak": true,
+	"Buginese": true, "Buhid": true, "Canadian_Aboriginal": true,
+	"Chakma": true, "Cham": true, "Hanifi_Rohingya": true, "Javanese": true,
+	"Kayah_Li": true, "Lepcha": true, "Limbu": true, "Lisu": true,
+	"Mandaic": true, "Meetei_Mayek": true, "Miao": true, "Mongolian": true,
+	"New_Tai_Lue": true, "Nko": true, "Phags_Pa": true, "Rejang": true,
+	"Samaritan": true, "Saurashtra": true, "Sora_Sompeng": true,
+	"Sundanese": true, "Syloti_Nagri": true, "Syriac": true, "Tagalog": true,
+	"Tagbanwa": true, "Tai_Le": true, "Tai_Tham": true, "Tai_Viet": true,
+	"Takri": true, "Thaana": true, "Tifinagh": true, "Vai": true,
+	"Wancho": true, "Warang_Citi": true,
+}
+
+// aspirationalScripts is TR39's small Aspirational Use script list
+// (http://www.unicode.org/reports/tr39/#Aspirational_Use_Scripts): scripts
+// that count alongside Recommended ones for ModeratelyRestrictive, but
+// aren't Recommended themselves.
+var aspirationalScripts = map[string]bool{
+	"Cherokee": true, "Glagolitic": true, "Ol_Chiki": true, "Osage": true,
+}
+
+// moderatelyRestrictiveScripts is the union of Recommended and Aspirational
+// scripts: Latin plus any one of these is ModeratelyRestrictive, provided
+// it isn't also Excluded/Limited_Use.
+var moderatelyRestrictiveScripts = unionScripts(recommendedScripts, aspirationalScripts)
+
+func unionScripts(sets ...map[string]bool) map[string]bool {
+	out := make(map[string]bool)
+	for _, set := range sets {
+		for name := range set {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// highlyRestrictiveSets are the logogram combinations, each paired with
+// Latin, that TR39 allows at the HighlyRestrictive level regardless of the
+// Recommended-script rule.
+var highlyRestrictiveSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// ignorableScript reports whether a script name should be treated as
+// matching any other script, per TR39's treatment of Common and Inherited.
+func ignorableScript(name string) bool {
+	return name == "Common" || name == "Inherited"
+}
+
+// resolvedScriptSets returns, for each character of s whose resolved script
+// set isn't entirely Common/Inherited, the set of scripts it could belong
+// to.
+func resolvedScriptSets(s string) []map[string]bool {
+	var sets []map[string]bool
+	for _, r := range s {
+		set := make(map[string]bool)
+		for _, name := range scriptsOf(r) {
+			if !ignorableScript(name) {
+				set[name] = true
+			}
+		}
+		if len(set) > 0 {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+func intersect(a, b map[string]bool) map[string]bool {
+	out := make(map[string]bool)
+	for name := range a {
+		if b[name] {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// commonScripts returns the scripts shared by every set in sets, or nil if
+// sets is empty or they share nothing.
+func commonScripts(sets []map[string]bool) map[string]bool {
+	if len(sets) == 0 {
+		return nil
+	}
+	common := sets[0]
+	for _, set := range sets[1:] {
+		common = intersect(common, set)
+		if len(common) == 0 {
+			return nil
+		}
+	}
+	return common
+}
+
+// coveredBy reports whether every set in sets intersects allowed.
+func coveredBy(sets []map[string]bool, allowed map[string]bool) bool {
+	for _, set := range sets {
+		if len(intersect(set, allowed)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+func isASCIIOnly(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// RestrictionLevel classifies s per Unicode TR39 §5, using the script sets
+// resolved from Unicode's Script and Script_Extensions properties.
+func RestrictionLevel(s string) IdentifierType {
+	if isASCIIOnly(s) {
+		return ASCIIOnly
+	}
+
+	sets := resolvedScriptSets(s)
+
+	if len(sets) == 0 {
+		// Every non-ASCII character resolved to Common/Inherited only (e.g.
+		// a bare combining mark): there's no script to disagree on.
+		return SingleScript
+	}
+	if len(commonScripts(sets)) > 0 {
+		return SingleScript
+	}
+
+	for _, combo := range highlyRestrictiveSets {
+		if coveredBy(sets, toSet(combo)) {
+			return HighlyRestrictive
+		}
+	}
+	if coveredByLatinPlusOne(sets, recommendedScripts) {
+		return HighlyRestrictive
+	}
+	if coveredByLatinPlusOne(sets, moderatelyRestrictiveScripts) {
+		return ModeratelyRestrictive
+	}
+
+	for _, set := range sets {
+		for name := range set {
+			if excludedOrLimitedUseScripts[name] {
+				return Unrestricted
+			}
+		}
+	}
+	return MinimallyRestrictive
+}
+
+// coveredByLatinPlusOne reports whether sets is covered by Latin together
+// with exactly one other script that is a member of allowedOthers (e.g.
+// recommendedScripts for HighlyRestrictive, or moderatelyRestrictiveScripts
+// for ModeratelyRestrictive). Callers must not pass a nil or unrestricted
+// allowedOthers: that would let Excluded/Limited_Use scripts slip through
+// as an allowed "other" script before RestrictionLevel's exclusion check
+// ever runs.
+func coveredByLatinPlusOne(sets []map[string]bool, allowedOthers map[string]bool) bool {
+	candidates := make(map[string]bool)
+	for _, set := range sets {
+		for name := range set {
+			if name == "Latin" {
+				continue
+			}
+			if allowedOthers[name] {
+				candidates[name] = true
+			}
+		}
+	}
+	for other := range candidates {
+		if coveredBy(sets, toSet([]string{"Latin", other})) {
+			return true
+		}
+	}
+	return coveredBy(sets, toSet([]string{"Latin"}))
+}
+
+// AllowedByLevel reports whether s satisfies at least the given maximum
+// restriction level, i.e. RestrictionLevel(s) <= max.
+func AllowedByLevel(s string, max IdentifierType) bool {
+	return RestrictionLevel(s) <= max
+}
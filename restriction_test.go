@@ -0,0 +1,47 @@
+package confusables
+
+import "testing"
+
+func TestRestrictionLevelASCII(t *testing.T) {
+	if level := RestrictionLevel("paypal123"); level != ASCIIOnly {
+		t.Errorf("RestrictionLevel(%q) = %s, want %s", "paypal123", level, ASCIIOnly)
+	}
+}
+
+func TestAllowedByLevel(t *testing.T) {
+	if !AllowedByLevel("paypal", HighlyRestrictive) {
+		t.Errorf("expected ASCII identifier to be allowed at HighlyRestrictive")
+	}
+}
+
+func TestRestrictionLevelCombiningMarkOnly(t *testing.T) {
+	// A bare combining mark resolves to Inherited only; TR39 treats
+	// Common/Inherited as matching any script, so this must not fall
+	// through to the (vacuously true) HighlyRestrictive combo checks.
+	if level := RestrictionLevel("́"); level != SingleScript {
+		t.Errorf("RestrictionLevel(%+q) = %s, want %s", "́", level, SingleScript)
+	}
+}
+
+func TestRestrictionLevelVectors(t *testing.T) {
+	vectors := []struct {
+		name  string
+		s     string
+		level IdentifierType
+	}{
+		{"pure Cyrillic", "Кириллица", SingleScript},
+		{"Han+Katakana", "日本語テスト", HighlyRestrictive},
+		{"Han+Hangul", "大韓민국", HighlyRestrictive},
+		{"Han+Bopomofo", "國ㄍㄨㄛˊ", HighlyRestrictive},
+		{"Latin+Cherokee (aspirational)", "aᎠ", ModeratelyRestrictive},
+		{"Latin+Thaana (limited use)", "aހ", Unrestricted},
+		{"three unrelated recommended scripts", "αбա", MinimallyRestrictive},
+		{"Gothic+Cyrillic", "\U00010330а", Unrestricted},
+	}
+
+	for _, v := range vectors {
+		if level := RestrictionLevel(v.s); level != v.level {
+			t.Errorf("%s: RestrictionLevel(%+q) = %s, want %s", v.name, v.s, level, v.level)
+		}
+	}
+}
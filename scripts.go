@@ -0,0 +1,29 @@
+package confusables
+
+import "unicode"
+
+// scriptRanges maps a Unicode script name to the set of runes whose primary
+// Script property is that script. It is generated from Scripts.txt; see
+// maketables.go.
+//
+// scriptExtensions maps a rune to its Script_Extensions set, which takes the
+// place of its Script property, for the minority of runes listed in
+// ScriptExtensions.txt (those whose Script_Extensions differ from their
+// Script). Runes absent from this map resolve to their single entry in
+// scriptRanges.
+//
+// Both tables live in tables.go, produced by `go generate`.
+
+// scriptsOf returns the resolved set of scripts a rune belongs to, i.e. its
+// Script property as extended by Script_Extensions.
+func scriptsOf(r rune) []string {
+	if ext, ok := scriptExtensions[r]; ok {
+		return ext
+	}
+	for name, table := range scriptRanges {
+		if unicode.Is(table, r) {
+			return []string{name}
+		}
+	}
+	return nil
+}
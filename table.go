@@ -0,0 +1,193 @@
+package confusables
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Table holds a set of rune -> replacement confusable mappings that
+// Skeleton can be computed against. The built-in mappings are hardcoded
+// into tables.go at code-gen time (see maketables.go); Table lets callers
+// tune that policy for their own domain instead, or pin a specific Unicode
+// version at runtime with LoadFromReader. A Table is safe for concurrent
+// use by multiple goroutines, including a Skeleton call racing an Add or
+// Remove.
+type Table struct {
+	mu sync.RWMutex
+	m  map[rune]string
+}
+
+// NewTable returns an empty Table with no confusable mappings.
+func NewTable() *Table {
+	return &Table{m: make(map[rune]string)}
+}
+
+// Add registers r as confusable with, and replaced by, s.
+func (t *Table) Add(r rune, s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.m[r] = s
+}
+
+// Remove removes any confusable mapping registered for r.
+func (t *Table) Remove(r rune) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.m, r)
+}
+
+// Skeleton converts s to its skeleton form using t's mappings, as described
+// in http://www.unicode.org/reports/tr39/#Confusable_Detection
+func (t *Table) Skeleton(s string) string {
+	normalized := norm.NFKD.String(s)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var buf strings.Builder
+	buf.Grow(len(normalized))
+	for i, w := 0, 0; i < len(normalized); i += w {
+		r, width := utf8.DecodeRuneInString(normalized[i:])
+		w = width
+		if replacement, ok := t.m[r]; ok {
+			buf.WriteString(replacement)
+		} else {
+			buf.WriteString(normalized[i : i+width])
+		}
+	}
+
+	return norm.NFKD.String(buf.String())
+}
+
+// SkeletonBytes appends the skeleton form of src to dst and returns the
+// extended buffer, in the style of norm.Form.Append, using t's mappings.
+func (t *Table) SkeletonBytes(dst, src []byte) []byte {
+	normalized := norm.NFKD.Append(nil, src...)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	buf := make([]byte, 0, len(normalized))
+	for i, w := 0, 0; i < len(normalized); i += w {
+		r, width := utf8.DecodeRune(normalized[i:])
+		w = width
+		if replacement, ok := t.m[r]; ok {
+			buf = append(buf, replacement...)
+		} else {
+			buf = append(buf, normalized[i:i+width]...)
+		}
+	}
+
+	return norm.NFKD.Append(dst, buf...)
+}
+
+// NewSkeletonWriter returns a ready-to-use SkeletonWriter backed by t.
+func (t *Table) NewSkeletonWriter() *SkeletonWriter {
+	return &SkeletonWriter{table: t}
+}
+
+// Clone returns an independent copy of t's mappings. Use it to get a
+// private table to customize with Add/Remove, e.g.
+// confusables.DefaultTable().Clone(), instead of mutating a shared Table
+// that other code in the process may also be using.
+func (t *Table) Clone() *Table {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	m := make(map[rune]string, len(t.m))
+	for r, s := range t.m {
+		m[r] = s
+	}
+	return &Table{m: m}
+}
+
+// newDefaultTable copies confusablesMap into a fresh Table rather than
+// aliasing the generated package-level map directly, so that customizing
+// DefaultTable() via Add/Remove can never mutate the shared global map
+// underneath concurrent Skeleton/SkeletonBytes/SkeletonWriter callers.
+func newDefaultTable() *Table {
+	m := make(map[rune]string, len(confusablesMap))
+	for r, s := range confusablesMap {
+		m[r] = s
+	}
+	return &Table{m: m}
+}
+
+var defaultTable = newDefaultTable()
+
+// DefaultTable returns the package's built-in confusable table, generated
+// from Unicode's confusables.txt (see maketables.go). It is a process-wide
+// singleton backing the package-level Skeleton/SkeletonBytes/SkeletonWriter
+// functions: calling Add or Remove on the returned Table changes what every
+// caller of those functions sees, everywhere in the process, not just your
+// own copy. If you need to customize the mappings for just your own use
+// (e.g. two unrelated packages in the same binary each tuning the policy
+// for their own domain), call DefaultTable().Clone() first and customize
+// the clone instead.
+func DefaultTable() *Table {
+	return defaultTable
+}
+
+// LoadFromReader parses r as an upstream confusables.txt file, in the
+// format published at http://www.unicode.org/Public/security/latest/, and
+// returns a Table built from its MA ("confusable") entries. This lets a
+// caller pin a specific Unicode version, or a custom variant of the data,
+// at runtime instead of at code-gen time.
+func LoadFromReader(r io.Reader) (*Table, error) {
+	t := NewTable()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		fields := strings.Split(line, " ;\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		typ := strings.TrimSpace(strings.SplitN(fields[2], "#", 2)[0])
+		if !strings.HasPrefix(typ, "MA") {
+			// The MA table is a superset anyway.
+			continue
+		}
+
+		source, err := parseConfusableCodePoint(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("confusables: %q: %v", line, err)
+		}
+
+		var target strings.Builder
+		for _, cp := range strings.Fields(fields[1]) {
+			r, err := parseConfusableCodePoint(cp)
+			if err != nil {
+				return nil, fmt.Errorf("confusables: %q: %v", line, err)
+			}
+			target.WriteRune(r)
+		}
+
+		t.Add(source, target.String())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func parseConfusableCodePoint(s string) (rune, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	return rune(n), nil
+}
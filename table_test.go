@@ -0,0 +1,67 @@
+package confusables
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableAddRemove(t *testing.T) {
+	tbl := NewTable()
+	tbl.Add('0', "o")
+
+	if got, expected := tbl.Skeleton("0"), "o"; got != expected {
+		t.Errorf("Skeleton(%q) = %q, want %q", "0", got, expected)
+	}
+
+	tbl.Remove('0')
+	if got, expected := tbl.Skeleton("0"), "0"; got != expected {
+		t.Errorf("after Remove, Skeleton(%q) = %q, want %q", "0", got, expected)
+	}
+}
+
+func TestDefaultTableMatchesSkeleton(t *testing.T) {
+	s := "ρ⍺у𝓅𝒂ן"
+	if got, expected := DefaultTable().Skeleton(s), Skeleton(s); got != expected {
+		t.Errorf("DefaultTable().Skeleton(%q) = %q, want %q", s, got, expected)
+	}
+}
+
+func TestTableSkeletonBytesAndWriter(t *testing.T) {
+	tbl := NewTable()
+	tbl.Add('0', "o")
+
+	if got, expected := string(tbl.SkeletonBytes(nil, []byte("0"))), "o"; got != expected {
+		t.Errorf("SkeletonBytes(%q) = %q, want %q", "0", got, expected)
+	}
+
+	w := tbl.NewSkeletonWriter()
+	w.Write([]byte("0"))
+	if got, expected := w.String(), "o"; got != expected {
+		t.Errorf("SkeletonWriter.String() = %q, want %q", got, expected)
+	}
+}
+
+func TestTableCloneIsIndependent(t *testing.T) {
+	clone := DefaultTable().Clone()
+	clone.Add('0', "o")
+
+	if got, expected := clone.Skeleton("0"), "o"; got != expected {
+		t.Errorf("clone.Skeleton(%q) = %q, want %q", "0", got, expected)
+	}
+	if got, expected := DefaultTable().Skeleton("0"), "O"; got != expected {
+		t.Errorf("mutating a clone changed DefaultTable(): Skeleton(%q) = %q, want %q", "0", got, expected)
+	}
+}
+
+func TestLoadFromReader(t *testing.T) {
+	const data = "0041 ;\t0061 ;\tMA\t#* ( A → a ) LATIN CAPITAL LETTER A → LATIN SMALL LETTER A\n"
+
+	tbl, err := LoadFromReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	if got, expected := tbl.Skeleton("A"), "a"; got != expected {
+		t.Errorf("Skeleton(%q) = %q, want %q", "A", got, expected)
+	}
+}